@@ -0,0 +1,57 @@
+package pvd
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/mux"
+)
+
+var (
+	activeStrategyMu sync.RWMutex
+	activeStrategy   *Strategy
+	reportRouters    = make(map[*mux.Router]bool)
+)
+
+// SetupHTTPRoutes registers GET /strategies/pvdot/report on the router bbgo's
+// trader already serves its built-in routes from, which is how the report
+// endpoint ends up reachable from the process's real HTTP server instead of
+// one this strategy would have to run itself.
+//
+// On a given router the first handler bound to a path wins, so the path is
+// bound only once per router and the handler doesn't close over s directly:
+// it reads a package-level pointer that SetupHTTPRoutes updates on every
+// call. That's what lets a later instance -- a second live session, a new
+// backtest run in the same process -- take over from an earlier one instead
+// of being silently shadowed by it, whether it is set up on the same router
+// or on a new one.
+func (s *Strategy) SetupHTTPRoutes(router *mux.Router) {
+	activeStrategyMu.Lock()
+	activeStrategy = s
+	registered := reportRouters[router]
+	reportRouters[router] = true
+	activeStrategyMu.Unlock()
+
+	if registered {
+		return
+	}
+
+	router.HandleFunc("/strategies/pvdot/report", func(w http.ResponseWriter, r *http.Request) {
+		activeStrategyMu.RLock()
+		strategy := activeStrategy
+		activeStrategyMu.RUnlock()
+
+		strategy.reportHandler()(w, r)
+	}).Methods(http.MethodGet)
+}
+
+// reportHandler returns the PortfolioTracker's current report as JSON.
+func (s *Strategy) reportHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(s.tracker.Report()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package pvd
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/gorilla/mux"
+)
+
+func recordReport(t *testing.T, ordersSubmitted int) *PortfolioTracker {
+	t.Helper()
+
+	tracker, err := NewPortfolioTracker(PortfolioTrackerConfig{})
+	if err != nil {
+		t.Fatalf("NewPortfolioTracker() error = %v", err)
+	}
+
+	tracker.Record(
+		time.Now(),
+		map[string]fixedpoint.Value{"BTC": fixedpoint.NewFromFloat(50000)},
+		map[string]fixedpoint.Value{"BTC": fixedpoint.NewFromFloat(1)},
+		map[string]fixedpoint.Value{"BTC": fixedpoint.NewFromFloat(50000)},
+		map[string]fixedpoint.Value{"BTC": fixedpoint.NewFromFloat(0.9)},
+		map[string]fixedpoint.Value{"BTC": fixedpoint.NewFromFloat(1.0)},
+		map[string]fixedpoint.Value{"BTC": fixedpoint.NewFromFloat(0.1)},
+		ordersSubmitted,
+		fixedpoint.NewFromFloat(5),
+	)
+	return tracker
+}
+
+func TestStrategy_ReportRouteServesThroughRealRouter(t *testing.T) {
+	router := mux.NewRouter()
+	s := &Strategy{tracker: recordReport(t, 1)}
+	s.SetupHTTPRoutes(router)
+
+	req := httptest.NewRequest("GET", "/strategies/pvdot/report", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != 200 {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+
+	var report Report
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatalf("failed to decode report: %v", err)
+	}
+	if len(report.Snapshots) != 1 {
+		t.Fatalf("expected 1 snapshot, got %d", len(report.Snapshots))
+	}
+	if report.Snapshots[0].OrdersSubmitted != 1 {
+		t.Errorf("expected 1 order submitted, got %d", report.Snapshots[0].OrdersSubmitted)
+	}
+}
+
+func TestStrategy_ReportRouteFollowsLatestInstance(t *testing.T) {
+	router := mux.NewRouter()
+
+	first := &Strategy{tracker: recordReport(t, 1)}
+	first.SetupHTTPRoutes(router)
+
+	second := &Strategy{tracker: recordReport(t, 7)}
+	second.SetupHTTPRoutes(router)
+
+	req := httptest.NewRequest("GET", "/strategies/pvdot/report", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	var report Report
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatalf("failed to decode report: %v", err)
+	}
+	if got := report.Snapshots[0].OrdersSubmitted; got != 7 {
+		t.Errorf("expected the route to serve the most recently set up instance's report (7 orders), got %d", got)
+	}
+}
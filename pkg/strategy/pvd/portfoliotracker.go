@@ -0,0 +1,282 @@
+package pvd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+// PortfolioSnapshot is a single point-in-time record of the portfolio state,
+// taken on every kline close for which every currency's price resolves.
+type PortfolioSnapshot struct {
+	Time            time.Time                   `json:"time"`
+	Prices          map[string]fixedpoint.Value `json:"prices"`
+	Quantities      map[string]fixedpoint.Value `json:"quantities"`
+	MarketValues    map[string]fixedpoint.Value `json:"marketValues"`
+	CurrentWeights  map[string]fixedpoint.Value `json:"currentWeights"`
+	TargetWeights   map[string]fixedpoint.Value `json:"targetWeights"`
+	OrdersSubmitted int                         `json:"ordersSubmitted"`
+	RealizedFees    fixedpoint.Value            `json:"realizedFees"`
+
+	// Turnover is 0.5*sum(|tradedWeightDelta|) for this snapshot: the weight
+	// actually moved by the orders that were submitted, not the full
+	// remaining drift to target.
+	Turnover fixedpoint.Value `json:"turnover"`
+	// TrackingError is sum((targetWeight - currentWeight)^2) for this snapshot.
+	TrackingError fixedpoint.Value `json:"trackingError"`
+}
+
+// ReportSink persists portfolio snapshots somewhere durable, e.g. a CSV or
+// JSON-lines file on disk.
+type ReportSink interface {
+	Write(snapshot PortfolioSnapshot) error
+}
+
+// PortfolioTrackerConfig configures the PortfolioTracker's in-memory buffer
+// size and optional file sink.
+type PortfolioTrackerConfig struct {
+	// OutputPath, if set, additionally persists every snapshot to a CSV
+	// (".csv") or JSON-lines (any other extension) file at this path.
+	OutputPath string `json:"outputPath"`
+
+	// RingBufferSize bounds how many snapshots are kept in memory. Defaults
+	// to 1000 when left at 0.
+	RingBufferSize int `json:"ringBufferSize"`
+}
+
+// PortfolioTracker records a PortfolioSnapshot for every rebalance into an
+// in-memory ring buffer, optionally mirroring it to a CSV/JSON sink, and
+// derives rolling turnover, cumulative tracking-error, and per-asset return
+// contribution metrics.
+type PortfolioTracker struct {
+	mu sync.Mutex
+
+	ringSize int
+	buffer   []PortfolioSnapshot
+	sink     ReportSink
+
+	cumulativeTrackingError fixedpoint.Value
+}
+
+func NewPortfolioTracker(config PortfolioTrackerConfig) (*PortfolioTracker, error) {
+	ringSize := config.RingBufferSize
+	if ringSize <= 0 {
+		ringSize = 1000
+	}
+
+	tracker := &PortfolioTracker{
+		ringSize:                ringSize,
+		cumulativeTrackingError: fixedpoint.NewFromFloat(0.0),
+	}
+
+	if config.OutputPath != "" {
+		sink, err := newFileReportSink(config.OutputPath)
+		if err != nil {
+			return nil, err
+		}
+		tracker.sink = sink
+	}
+
+	return tracker, nil
+}
+
+// Record computes turnover and tracking error for the given rebalance and
+// appends the resulting snapshot to the ring buffer and the configured sink.
+//
+// tradedWeightDelta holds the signed weight change for each currency an
+// order was actually generated for (see generateSubmitOrders); currencies
+// skipped by the no-trade band, MinHoldingTime, min notional, or the cost
+// model have no entry and so contribute nothing to turnover. trackingError
+// still compares the full currentWeights/targetWeights gap, since that's
+// meant to capture how far the portfolio actually sits from target,
+// regardless of what was traded this round.
+func (t *PortfolioTracker) Record(
+	now time.Time,
+	prices, quantities, marketValues, currentWeights, targetWeights, tradedWeightDelta map[string]fixedpoint.Value,
+	ordersSubmitted int,
+	realizedFees fixedpoint.Value,
+) PortfolioSnapshot {
+	turnover := fixedpoint.NewFromFloat(0.0)
+	for _, delta := range tradedWeightDelta {
+		turnover = turnover.Add(delta.Abs())
+	}
+	turnover = turnover.Div(fixedpoint.NewFromFloat(2.0))
+
+	trackingError := fixedpoint.NewFromFloat(0.0)
+	for currency, target := range targetWeights {
+		diff := target.Sub(currentWeights[currency]).Abs()
+		trackingError = trackingError.Add(diff.Mul(diff))
+	}
+
+	snapshot := PortfolioSnapshot{
+		Time:            now,
+		Prices:          prices,
+		Quantities:      quantities,
+		MarketValues:    marketValues,
+		CurrentWeights:  currentWeights,
+		TargetWeights:   targetWeights,
+		OrdersSubmitted: ordersSubmitted,
+		RealizedFees:    realizedFees,
+		Turnover:        turnover,
+		TrackingError:   trackingError,
+	}
+
+	t.mu.Lock()
+	t.cumulativeTrackingError = t.cumulativeTrackingError.Add(trackingError)
+	t.buffer = append(t.buffer, snapshot)
+	if len(t.buffer) > t.ringSize {
+		t.buffer = t.buffer[len(t.buffer)-t.ringSize:]
+	}
+	t.mu.Unlock()
+
+	if t.sink != nil {
+		if err := t.sink.Write(snapshot); err != nil {
+			log.WithError(err).Error("portfolio report sink write error")
+		}
+	}
+
+	return snapshot
+}
+
+// Report summarizes the tracked history: the snapshots currently in the ring
+// buffer, the cumulative tracking error across all recorded snapshots, and
+// each asset's contribution to portfolio return over the buffered window.
+type Report struct {
+	Snapshots               []PortfolioSnapshot         `json:"snapshots"`
+	CumulativeTrackingError fixedpoint.Value            `json:"cumulativeTrackingError"`
+	AssetContributions      map[string]fixedpoint.Value `json:"assetContributions"`
+}
+
+func (t *PortfolioTracker) Report() Report {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	snapshots := make([]PortfolioSnapshot, len(t.buffer))
+	copy(snapshots, t.buffer)
+
+	return Report{
+		Snapshots:               snapshots,
+		CumulativeTrackingError: t.cumulativeTrackingError,
+		AssetContributions:      assetContributions(snapshots),
+	}
+}
+
+// assetContributions approximates each asset's contribution to the
+// portfolio's total return over the buffered window as its average market
+// value weight times its own price return.
+func assetContributions(snapshots []PortfolioSnapshot) map[string]fixedpoint.Value {
+	contributions := make(map[string]fixedpoint.Value)
+	if len(snapshots) < 2 {
+		return contributions
+	}
+
+	first, last := snapshots[0], snapshots[len(snapshots)-1]
+	for currency, startPrice := range first.Prices {
+		endPrice, ok := last.Prices[currency]
+		if !ok || startPrice.Sign() == 0 {
+			continue
+		}
+
+		assetReturn := endPrice.Sub(startPrice).Div(startPrice)
+		avgWeight := first.CurrentWeights[currency].Add(last.CurrentWeights[currency]).Div(fixedpoint.NewFromFloat(2.0))
+		contributions[currency] = avgWeight.Mul(assetReturn)
+	}
+	return contributions
+}
+
+// fileReportSink mirrors snapshots to a CSV or JSON-lines file, appending on
+// every write.
+type fileReportSink struct {
+	mu     sync.Mutex
+	format string // "csv" or "json"
+	file   *os.File
+	writer *csv.Writer
+	header bool
+}
+
+func newFileReportSink(path string) (*fileReportSink, error) {
+	format := "json"
+	if strings.HasSuffix(strings.ToLower(path), ".csv") {
+		format = "csv"
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("pvd: unable to open report output path %s: %w", path, err)
+	}
+
+	sink := &fileReportSink{format: format, file: file}
+	if format == "csv" {
+		sink.writer = csv.NewWriter(file)
+	}
+	return sink, nil
+}
+
+func (s *fileReportSink) Write(snapshot PortfolioSnapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.format == "json" {
+		return json.NewEncoder(s.file).Encode(snapshot)
+	}
+
+	// the per-currency fields (prices, quantities, ...) don't have a fixed
+	// set of columns across snapshots, so they're written as JSON blobs in
+	// their own column rather than dropped from the CSV output.
+	if !s.header {
+		if err := s.writer.Write([]string{
+			"time", "turnover", "trackingError", "ordersSubmitted", "realizedFees",
+			"prices", "quantities", "marketValues", "currentWeights", "targetWeights",
+		}); err != nil {
+			return err
+		}
+		s.header = true
+	}
+
+	prices, err := json.Marshal(snapshot.Prices)
+	if err != nil {
+		return err
+	}
+	quantities, err := json.Marshal(snapshot.Quantities)
+	if err != nil {
+		return err
+	}
+	marketValues, err := json.Marshal(snapshot.MarketValues)
+	if err != nil {
+		return err
+	}
+	currentWeights, err := json.Marshal(snapshot.CurrentWeights)
+	if err != nil {
+		return err
+	}
+	targetWeights, err := json.Marshal(snapshot.TargetWeights)
+	if err != nil {
+		return err
+	}
+
+	record := []string{
+		snapshot.Time.Format(time.RFC3339),
+		strconv.FormatFloat(snapshot.Turnover.Float64(), 'f', -1, 64),
+		strconv.FormatFloat(snapshot.TrackingError.Float64(), 'f', -1, 64),
+		strconv.Itoa(snapshot.OrdersSubmitted),
+		strconv.FormatFloat(snapshot.RealizedFees.Float64(), 'f', -1, 64),
+		string(prices),
+		string(quantities),
+		string(marketValues),
+		string(currentWeights),
+		string(targetWeights),
+	}
+	if err := s.writer.Write(record); err != nil {
+		return err
+	}
+
+	s.writer.Flush()
+	return s.writer.Error()
+}
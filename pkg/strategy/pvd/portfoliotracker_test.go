@@ -0,0 +1,118 @@
+package pvd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+func TestPortfolioTracker_RecordComputesTurnoverAndTrackingError(t *testing.T) {
+	tracker, err := NewPortfolioTracker(PortfolioTrackerConfig{})
+	if err != nil {
+		t.Fatalf("NewPortfolioTracker() error = %v", err)
+	}
+
+	currentWeights := map[string]fixedpoint.Value{
+		"BTC": fixedpoint.NewFromFloat(0.4),
+		"ETH": fixedpoint.NewFromFloat(0.6),
+	}
+	targetWeights := map[string]fixedpoint.Value{
+		"BTC": fixedpoint.NewFromFloat(0.5),
+		"ETH": fixedpoint.NewFromFloat(0.5),
+	}
+	// only BTC's order actually went through this round -- e.g. ETH's
+	// candidate trade was skipped by MinHoldingTime or the cost model.
+	tradedWeightDelta := map[string]fixedpoint.Value{
+		"BTC": fixedpoint.NewFromFloat(0.1),
+	}
+
+	snapshot := tracker.Record(time.Now(), nil, nil, nil, currentWeights, targetWeights, tradedWeightDelta, 1, fixedpoint.NewFromFloat(0))
+
+	// turnover = 0.5 * |0.1| = 0.05, not 0.5 * (|0.5-0.4| + |0.5-0.6|) = 0.1,
+	// since ETH's trade never happened.
+	if got := snapshot.Turnover.Float64(); got < 0.0499 || got > 0.0501 {
+		t.Errorf("Turnover = %v, want ~0.05", got)
+	}
+
+	// trackingError = (0.1)^2 + (0.1)^2 = 0.02
+	if got := snapshot.TrackingError.Float64(); got < 0.0199 || got > 0.0201 {
+		t.Errorf("TrackingError = %v, want ~0.02", got)
+	}
+
+	report := tracker.Report()
+	if report.CumulativeTrackingError.Float64() < 0.0199 {
+		t.Errorf("CumulativeTrackingError = %v, want ~0.02 after one snapshot", report.CumulativeTrackingError.Float64())
+	}
+}
+
+func TestPortfolioTracker_RingBufferBound(t *testing.T) {
+	tracker, err := NewPortfolioTracker(PortfolioTrackerConfig{RingBufferSize: 2})
+	if err != nil {
+		t.Fatalf("NewPortfolioTracker() error = %v", err)
+	}
+
+	weights := map[string]fixedpoint.Value{"BTC": fixedpoint.One}
+	for i := 0; i < 5; i++ {
+		tracker.Record(time.Now(), nil, nil, nil, weights, weights, nil, 0, fixedpoint.NewFromFloat(0))
+	}
+
+	if got := len(tracker.Report().Snapshots); got != 2 {
+		t.Errorf("expected ring buffer to cap at 2 snapshots, got %d", got)
+	}
+}
+
+func TestPortfolioTracker_FileSinkJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.json")
+	tracker, err := NewPortfolioTracker(PortfolioTrackerConfig{OutputPath: path})
+	if err != nil {
+		t.Fatalf("NewPortfolioTracker() error = %v", err)
+	}
+
+	weights := map[string]fixedpoint.Value{"BTC": fixedpoint.One}
+	tracker.Record(time.Now(), weights, weights, weights, weights, weights, weights, 1, fixedpoint.NewFromFloat(0))
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read output file: %v", err)
+	}
+
+	var snapshot PortfolioSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		t.Fatalf("failed to decode written snapshot: %v", err)
+	}
+	if snapshot.OrdersSubmitted != 1 {
+		t.Errorf("OrdersSubmitted = %d, want 1", snapshot.OrdersSubmitted)
+	}
+}
+
+func TestPortfolioTracker_FileSinkCSVIncludesAllFields(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.csv")
+	tracker, err := NewPortfolioTracker(PortfolioTrackerConfig{OutputPath: path})
+	if err != nil {
+		t.Fatalf("NewPortfolioTracker() error = %v", err)
+	}
+
+	weights := map[string]fixedpoint.Value{"BTC": fixedpoint.One}
+	tracker.Record(time.Now(), weights, weights, weights, weights, weights, weights, 1, fixedpoint.NewFromFloat(0))
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read output file: %v", err)
+	}
+
+	content := string(data)
+	header := strings.Split(content, "\n")[0]
+	for _, column := range []string{"prices", "quantities", "marketValues", "currentWeights", "targetWeights"} {
+		if !strings.Contains(header, column) {
+			t.Errorf("expected CSV header to contain %q, got %q", column, header)
+		}
+	}
+	if !strings.Contains(content, "BTC") {
+		t.Errorf("expected CSV row to contain the per-asset data, got %q", content)
+	}
+}
@@ -0,0 +1,106 @@
+package pvd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// tickerQuerier is the subset of bbgo's exchange interface PriceResolver
+// needs. Defining it locally (instead of depending on the whole
+// *bbgo.ExchangeSession) keeps PriceResolver's fallback chain unit-testable
+// with a fake.
+type tickerQuerier interface {
+	QueryTicker(ctx context.Context, symbol string) (*types.Ticker, error)
+}
+
+// PriceResolverConfig configures how PriceResolver falls back to indirect
+// pricing when a currency has no direct currency+quote market on the
+// session's exchange.
+type PriceResolverConfig struct {
+	// Bridges lists assets that can be used as an intermediate hop for
+	// two-hop triangulation, e.g. X/BTC * BTC/USDT.
+	Bridges []string `json:"bridges"`
+
+	// StableProxies maps a quote currency to the stablecoins that can stand
+	// in for it when no direct market exists, e.g. USD: [USDT, USDC, BUSD].
+	StableProxies map[string][]string `json:"stableProxies"`
+}
+
+// PriceResolver resolves the price of a currency in terms of a quote
+// currency. It tries, in order: (1) the direct currency+quote market, (2)
+// one of quote's configured stablecoin proxies, and (3) two-hop
+// triangulation through a configured bridge asset, e.g. currency/BTC *
+// BTC/quote. It is shared by rebalance and is meant to be reused by future
+// PnL reporting as well.
+type PriceResolver struct {
+	PriceResolverConfig
+
+	exchange tickerQuerier
+}
+
+func NewPriceResolver(session *bbgo.ExchangeSession, config PriceResolverConfig) *PriceResolver {
+	return &PriceResolver{PriceResolverConfig: config, exchange: session.Exchange}
+}
+
+// Resolve returns the price of 1 unit of currency in terms of quote.
+func (r *PriceResolver) Resolve(ctx context.Context, currency, quote string) (fixedpoint.Value, error) {
+	if currency == quote {
+		return fixedpoint.One, nil
+	}
+
+	if price, err := r.queryTicker(ctx, currency+quote); err == nil {
+		return price, nil
+	}
+
+	for _, proxy := range r.StableProxies[quote] {
+		if price, err := r.queryTicker(ctx, currency+proxy); err == nil {
+			return price, nil
+		}
+	}
+
+	for _, bridge := range r.Bridges {
+		if bridge == currency || bridge == quote {
+			continue
+		}
+
+		toBridge, err := r.queryTicker(ctx, currency+bridge)
+		if err != nil {
+			continue
+		}
+
+		bridgeToQuote, err := r.resolveBridgeToQuote(ctx, bridge, quote)
+		if err != nil {
+			continue
+		}
+
+		return toBridge.Mul(bridgeToQuote), nil
+	}
+
+	return fixedpoint.NewFromFloat(0.0), fmt.Errorf("pvd: unable to resolve price for %s in %s", currency, quote)
+}
+
+func (r *PriceResolver) resolveBridgeToQuote(ctx context.Context, bridge, quote string) (fixedpoint.Value, error) {
+	if price, err := r.queryTicker(ctx, bridge+quote); err == nil {
+		return price, nil
+	}
+
+	for _, proxy := range r.StableProxies[quote] {
+		if price, err := r.queryTicker(ctx, bridge+proxy); err == nil {
+			return price, nil
+		}
+	}
+
+	return fixedpoint.NewFromFloat(0.0), fmt.Errorf("pvd: unable to resolve bridge %s to %s", bridge, quote)
+}
+
+func (r *PriceResolver) queryTicker(ctx context.Context, symbol string) (fixedpoint.Value, error) {
+	ticker, err := r.exchange.QueryTicker(ctx, symbol)
+	if err != nil {
+		return fixedpoint.NewFromFloat(0.0), err
+	}
+	return ticker.Last, nil
+}
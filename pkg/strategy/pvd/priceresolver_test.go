@@ -0,0 +1,116 @@
+package pvd
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// fakeTickerQuerier serves tickers from a fixed symbol->price map and errors
+// on anything not in it, mimicking an exchange with a limited market list.
+type fakeTickerQuerier struct {
+	prices map[string]float64
+}
+
+func (f *fakeTickerQuerier) QueryTicker(ctx context.Context, symbol string) (*types.Ticker, error) {
+	price, ok := f.prices[symbol]
+	if !ok {
+		return nil, fmt.Errorf("no market for %s", symbol)
+	}
+	return &types.Ticker{Last: fixedpoint.NewFromFloat(price)}, nil
+}
+
+func TestPriceResolver_DirectMarket(t *testing.T) {
+	r := &PriceResolver{exchange: &fakeTickerQuerier{prices: map[string]float64{"BTCUSD": 50000}}}
+
+	price, err := r.Resolve(context.Background(), "BTC", "USD")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if price.Float64() != 50000 {
+		t.Errorf("price = %v, want 50000", price.Float64())
+	}
+}
+
+func TestPriceResolver_SameCurrency(t *testing.T) {
+	r := &PriceResolver{exchange: &fakeTickerQuerier{}}
+
+	price, err := r.Resolve(context.Background(), "USD", "USD")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if price.Float64() != 1.0 {
+		t.Errorf("price = %v, want 1", price.Float64())
+	}
+}
+
+func TestPriceResolver_StableProxyFallback(t *testing.T) {
+	r := &PriceResolver{
+		PriceResolverConfig: PriceResolverConfig{
+			StableProxies: map[string][]string{"USD": {"USDT", "USDC"}},
+		},
+		exchange: &fakeTickerQuerier{prices: map[string]float64{"BTCUSDT": 50000}},
+	}
+
+	price, err := r.Resolve(context.Background(), "BTC", "USD")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if price.Float64() != 50000 {
+		t.Errorf("price = %v, want 50000", price.Float64())
+	}
+}
+
+func TestPriceResolver_BridgeTriangulation(t *testing.T) {
+	r := &PriceResolver{
+		PriceResolverConfig: PriceResolverConfig{
+			Bridges: []string{"BTC"},
+		},
+		exchange: &fakeTickerQuerier{prices: map[string]float64{
+			"SOLBTC": 0.002, // SOL/BTC
+			"BTCUSD": 50000, // BTC/USD
+		}},
+	}
+
+	price, err := r.Resolve(context.Background(), "SOL", "USD")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := 0.002 * 50000
+	if price.Float64() != want {
+		t.Errorf("price = %v, want %v", price.Float64(), want)
+	}
+}
+
+func TestPriceResolver_BridgeWithStableProxyOnSecondHop(t *testing.T) {
+	r := &PriceResolver{
+		PriceResolverConfig: PriceResolverConfig{
+			Bridges:       []string{"BTC"},
+			StableProxies: map[string][]string{"USD": {"USDT"}},
+		},
+		exchange: &fakeTickerQuerier{prices: map[string]float64{
+			"SOLBTC":  0.002,
+			"BTCUSDT": 51000,
+		}},
+	}
+
+	price, err := r.Resolve(context.Background(), "SOL", "USD")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := 0.002 * 51000
+	if price.Float64() != want {
+		t.Errorf("price = %v, want %v", price.Float64(), want)
+	}
+}
+
+func TestPriceResolver_Unresolvable(t *testing.T) {
+	r := &PriceResolver{exchange: &fakeTickerQuerier{}}
+
+	if _, err := r.Resolve(context.Background(), "ADA", "USD"); err == nil {
+		t.Errorf("expected an error when no direct, proxy, or bridge market exists")
+	}
+}
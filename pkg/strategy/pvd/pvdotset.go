@@ -0,0 +1,87 @@
+package pvd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// PVDotSet keeps a rolling window of klines for every quote currency market
+// (currency + BaseCurrency) and turns them into target portfolio weights
+// through the configured WeightingScheme.
+type PVDotSet struct {
+	types.IntervalWindow
+
+	session         *bbgo.ExchangeSession
+	BaseCurrency    string
+	QuoteCurrencies []string
+
+	// Weighting is the scheme used to derive target weights from the
+	// tracked kline windows. It defaults to PVDotWeighting if left nil.
+	Weighting WeightingScheme
+
+	series map[string]*types.KLineWindow
+}
+
+func (s *PVDotSet) InitIndicators(ctx context.Context) error {
+	if s.Weighting == nil {
+		s.Weighting = &PVDotWeighting{}
+	}
+
+	s.series = make(map[string]*types.KLineWindow)
+	for _, currency := range s.QuoteCurrencies {
+		symbol := currency + s.BaseCurrency
+
+		store, ok := s.session.MarketDataStore(symbol)
+		if !ok {
+			return fmt.Errorf("%s market data store not found", symbol)
+		}
+
+		klines, ok := store.KLinesOfInterval(s.Interval)
+		if !ok {
+			return fmt.Errorf("%s %s klines not found", symbol, s.Interval)
+		}
+
+		window := klines.Tail(s.Window)
+		s.series[currency] = &window
+	}
+	return nil
+}
+
+func (s *PVDotSet) Update(kline types.KLine) {
+	currency := currencyFromSymbol(kline.Symbol, s.BaseCurrency)
+	window, ok := s.series[currency]
+	if !ok {
+		return
+	}
+
+	window.Add(kline)
+	if window.Len() > s.Window {
+		*window = (*window)[window.Len()-s.Window:]
+	}
+}
+
+// TargetWeights derives the target portfolio weight of every quote currency
+// from the tracked kline windows, using the configured WeightingScheme.
+func (s *PVDotSet) TargetWeights() map[string]fixedpoint.Value {
+	if s.Weighting == nil {
+		s.Weighting = &PVDotWeighting{}
+	}
+
+	series := make(map[string]types.KLineWindow, len(s.series))
+	for currency, window := range s.series {
+		series[currency] = *window
+	}
+	return s.Weighting.Weights(series)
+}
+
+func currencyFromSymbol(symbol, baseCurrency string) string {
+	n := len(symbol) - len(baseCurrency)
+	if n <= 0 || symbol[n:] != baseCurrency {
+		return symbol
+	}
+	return symbol[:n]
+}
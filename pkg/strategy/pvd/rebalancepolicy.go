@@ -0,0 +1,115 @@
+package pvd
+
+import (
+	"strings"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+// Duration is a time.Duration that can be configured in YAML/JSON using a Go
+// duration string, e.g. "15m" or "2h".
+type Duration time.Duration
+
+func (d Duration) Duration() time.Duration {
+	return time.Duration(d)
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "" {
+		*d = 0
+		return nil
+	}
+
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(parsed)
+	return nil
+}
+
+// CostModel estimates the trading cost of a candidate order so the
+// rebalancer can skip trades whose expected cost outweighs the
+// tracking-error improvement they would buy.
+type CostModel struct {
+	// FeeRate is the taker fee charged by the exchange, e.g. 0.001 for 10bps.
+	FeeRate fixedpoint.Value `json:"feeRate"`
+
+	// SlippageRate is the assumed slippage of a market order, as a fraction
+	// of notional.
+	SlippageRate fixedpoint.Value `json:"slippageRate"`
+
+	// MaxCostRatio is the maximum fraction of a trade's tracking-error
+	// improvement (in quote currency) that its estimated cost may consume.
+	// Orders above this ratio are skipped.
+	MaxCostRatio fixedpoint.Value `json:"maxCostRatio"`
+}
+
+func (c *CostModel) estimatedCost(notional fixedpoint.Value) fixedpoint.Value {
+	return notional.Mul(c.FeeRate.Add(c.SlippageRate))
+}
+
+// allows reports whether notional's estimated fee+slippage cost is justified
+// by trackingErrorImprovement, the quote-currency tracking error the trade
+// removes.
+func (c *CostModel) allows(notional, trackingErrorImprovement fixedpoint.Value) bool {
+	if c == nil {
+		return true
+	}
+
+	cost := c.estimatedCost(notional)
+	return cost.Compare(trackingErrorImprovement.Mul(c.MaxCostRatio)) <= 0
+}
+
+// RebalancePolicy controls how aggressively the strategy trades back toward
+// target weights: how long to wait between rebalances of the same symbol,
+// and whether a trade's expected cost justifies sending it.
+//
+// The no-trade band itself reuses Strategy.Threshold: once the band is
+// breached, the rebalancer trades back only to the band edge instead of all
+// the way to the target weight, which keeps turnover down.
+type RebalancePolicy struct {
+	// MinHoldingTime is the minimum duration between two rebalances of the
+	// same symbol. A kline close within MinHoldingTime of the symbol's last
+	// rebalance is skipped even if its weight has drifted past Threshold.
+	MinHoldingTime Duration `json:"minHoldingTime"`
+
+	// CostModel, when set, skips orders whose estimated cost exceeds
+	// MaxCostRatio times their tracking-error improvement.
+	CostModel *CostModel `json:"costModel"`
+
+	lastRebalancedAt map[string]time.Time
+}
+
+func (p *RebalancePolicy) canRebalance(symbol string, now time.Time) bool {
+	last, ok := p.lastRebalancedAt[symbol]
+	if !ok {
+		return true
+	}
+
+	return now.Sub(last) >= p.MinHoldingTime.Duration()
+}
+
+func (p *RebalancePolicy) markRebalanced(symbol string, now time.Time) {
+	if p.lastRebalancedAt == nil {
+		p.lastRebalancedAt = make(map[string]time.Time)
+	}
+
+	p.lastRebalancedAt[symbol] = now
+}
+
+// bandEdgeTradeWeight returns the signed weight to trade so that currentWeight
+// lands on the edge of the no-trade band nearest currentWeight, rather than on
+// targetWeight itself. It is the caller's responsibility to only call this
+// once |targetWeight - currentWeight| has already been confirmed to exceed
+// threshold.
+func bandEdgeTradeWeight(currentWeight, targetWeight, threshold fixedpoint.Value) fixedpoint.Value {
+	bandEdgeWeight := targetWeight.Sub(threshold)
+	if targetWeight.Compare(currentWeight) < 0 {
+		bandEdgeWeight = targetWeight.Add(threshold)
+	}
+	return bandEdgeWeight.Sub(currentWeight)
+}
@@ -0,0 +1,70 @@
+package pvd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+func TestRebalancePolicy_CanRebalance(t *testing.T) {
+	p := &RebalancePolicy{MinHoldingTime: Duration(time.Hour)}
+	now := time.Now()
+
+	if !p.canRebalance("BTCUSD", now) {
+		t.Errorf("expected a symbol with no prior rebalance to be allowed")
+	}
+
+	p.markRebalanced("BTCUSD", now)
+	if p.canRebalance("BTCUSD", now.Add(30*time.Minute)) {
+		t.Errorf("expected rebalance to be blocked within MinHoldingTime")
+	}
+	if !p.canRebalance("BTCUSD", now.Add(61*time.Minute)) {
+		t.Errorf("expected rebalance to be allowed once MinHoldingTime has elapsed")
+	}
+	if !p.canRebalance("ETHUSD", now.Add(time.Minute)) {
+		t.Errorf("expected a different symbol to be unaffected by BTCUSD's last rebalance")
+	}
+}
+
+func TestCostModel_Allows(t *testing.T) {
+	var nilModel *CostModel
+	if !nilModel.allows(fixedpoint.NewFromFloat(1000), fixedpoint.NewFromFloat(0)) {
+		t.Errorf("a nil CostModel should always allow the trade")
+	}
+
+	model := &CostModel{
+		FeeRate:      fixedpoint.NewFromFloat(0.001),
+		SlippageRate: fixedpoint.NewFromFloat(0.001),
+		MaxCostRatio: fixedpoint.NewFromFloat(0.01),
+	}
+
+	notional := fixedpoint.NewFromFloat(1000)
+	// cost = 1000 * 0.002 = 2; improvement*ratio = 1000*0.01 = 10 -> allowed
+	if !model.allows(notional, fixedpoint.NewFromFloat(1000)) {
+		t.Errorf("expected trade to be allowed when cost is well below the tracking-error improvement budget")
+	}
+
+	// shrink the improvement so the same cost is no longer justified.
+	if model.allows(notional, fixedpoint.NewFromFloat(100)) {
+		t.Errorf("expected trade to be rejected when cost exceeds the tracking-error improvement budget")
+	}
+}
+
+func TestBandEdgeTradeWeight(t *testing.T) {
+	threshold := fixedpoint.NewFromFloat(0.05)
+
+	// current weight is below target: trade up to target-threshold, not to target.
+	tradeWeight := bandEdgeTradeWeight(fixedpoint.NewFromFloat(0.2), fixedpoint.NewFromFloat(0.3), threshold)
+	want := fixedpoint.NewFromFloat(0.3).Sub(threshold).Sub(fixedpoint.NewFromFloat(0.2))
+	if tradeWeight.Compare(want) != 0 {
+		t.Errorf("tradeWeight = %v, want %v", tradeWeight.Float64(), want.Float64())
+	}
+
+	// current weight is above target: trade down to target+threshold.
+	tradeWeight = bandEdgeTradeWeight(fixedpoint.NewFromFloat(0.4), fixedpoint.NewFromFloat(0.3), threshold)
+	want = fixedpoint.NewFromFloat(0.3).Add(threshold).Sub(fixedpoint.NewFromFloat(0.4))
+	if tradeWeight.Compare(want) != 0 {
+		t.Errorf("tradeWeight = %v, want %v", tradeWeight.Float64(), want.Float64())
+	}
+}
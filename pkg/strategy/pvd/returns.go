@@ -0,0 +1,163 @@
+package pvd
+
+import (
+	"math"
+	"sort"
+)
+
+// logReturns converts a slice of close prices into a slice of log returns.
+// The returned slice has one fewer element than closes, less one for every
+// consecutive pair that contains a non-positive price, which is skipped.
+func logReturns(closes []float64) []float64 {
+	if len(closes) < 2 {
+		return nil
+	}
+
+	returns := make([]float64, 0, len(closes)-1)
+	for i := 1; i < len(closes); i++ {
+		if closes[i-1] <= 0 || closes[i] <= 0 {
+			continue
+		}
+		returns = append(returns, math.Log(closes[i]/closes[i-1]))
+	}
+	return returns
+}
+
+// median returns the median of xs. It does not mutate xs.
+func median(xs []float64) float64 {
+	if len(xs) == 0 {
+		return 0.0
+	}
+
+	sorted := make([]float64, len(xs))
+	copy(sorted, xs)
+	sort.Float64s(sorted)
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[mid]
+	}
+	return (sorted[mid-1] + sorted[mid]) / 2.0
+}
+
+func mean(xs []float64) float64 {
+	if len(xs) == 0 {
+		return 0.0
+	}
+
+	sum := 0.0
+	for _, x := range xs {
+		sum += x
+	}
+	return sum / float64(len(xs))
+}
+
+// stddev returns the (population) standard deviation of xs.
+func stddev(xs []float64) float64 {
+	if len(xs) == 0 {
+		return 0.0
+	}
+
+	m := mean(xs)
+	var sumSq float64
+	for _, x := range xs {
+		d := x - m
+		sumSq += d * d
+	}
+	return math.Sqrt(sumSq / float64(len(xs)))
+}
+
+// covariance returns the (population) covariance between xs and ys.
+// xs and ys must be the same length.
+func covariance(xs, ys []float64) float64 {
+	n := len(xs)
+	if n == 0 || n != len(ys) {
+		return 0.0
+	}
+
+	mx, my := mean(xs), mean(ys)
+	var sum float64
+	for i := 0; i < n; i++ {
+		sum += (xs[i] - mx) * (ys[i] - my)
+	}
+	return sum / float64(n)
+}
+
+// covarianceMatrix computes the (population) covariance matrix of the given
+// currencies' return series, aligned by currencies order.
+func covarianceMatrix(currencies []string, returns map[string][]float64) [][]float64 {
+	n := len(currencies)
+	sigma := make([][]float64, n)
+	for i := range sigma {
+		sigma[i] = make([]float64, n)
+	}
+
+	for i, ci := range currencies {
+		for j, cj := range currencies {
+			sigma[i][j] = covariance(returns[ci], returns[cj])
+		}
+	}
+	return sigma
+}
+
+// invertMatrix computes the inverse of a square matrix using Gauss-Jordan
+// elimination. It returns false if the matrix is singular.
+func invertMatrix(m [][]float64) ([][]float64, bool) {
+	n := len(m)
+
+	aug := make([][]float64, n)
+	for i := 0; i < n; i++ {
+		aug[i] = make([]float64, 2*n)
+		copy(aug[i], m[i])
+		aug[i][n+i] = 1.0
+	}
+
+	for col := 0; col < n; col++ {
+		pivot := col
+		for row := col + 1; row < n; row++ {
+			if math.Abs(aug[row][col]) > math.Abs(aug[pivot][col]) {
+				pivot = row
+			}
+		}
+
+		if math.Abs(aug[pivot][col]) < 1e-12 {
+			return nil, false
+		}
+
+		aug[col], aug[pivot] = aug[pivot], aug[col]
+
+		pivotVal := aug[col][col]
+		for k := 0; k < 2*n; k++ {
+			aug[col][k] /= pivotVal
+		}
+
+		for row := 0; row < n; row++ {
+			if row == col {
+				continue
+			}
+			factor := aug[row][col]
+			for k := 0; k < 2*n; k++ {
+				aug[row][k] -= factor * aug[col][k]
+			}
+		}
+	}
+
+	inv := make([][]float64, n)
+	for i := 0; i < n; i++ {
+		inv[i] = make([]float64, n)
+		copy(inv[i], aug[i][n:])
+	}
+	return inv, true
+}
+
+func matVec(m [][]float64, v []float64) []float64 {
+	out := make([]float64, len(m))
+	for i := range m {
+		var sum float64
+		for j := range v {
+			sum += m[i][j] * v[j]
+		}
+		out[i] = sum
+	}
+	return out
+}
@@ -0,0 +1,104 @@
+package pvd
+
+import (
+	"math"
+	"testing"
+)
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestLogReturns(t *testing.T) {
+	returns := logReturns([]float64{100, 110, 99})
+	if len(returns) != 2 {
+		t.Fatalf("expected 2 returns, got %d", len(returns))
+	}
+	if !almostEqual(returns[0], math.Log(110.0/100.0)) {
+		t.Errorf("unexpected first return: %v", returns[0])
+	}
+	if !almostEqual(returns[1], math.Log(99.0/110.0)) {
+		t.Errorf("unexpected second return: %v", returns[1])
+	}
+
+	if logReturns([]float64{100}) != nil {
+		t.Errorf("expected nil returns for a single close price")
+	}
+}
+
+func TestStddev(t *testing.T) {
+	if stddev(nil) != 0.0 {
+		t.Errorf("expected 0 stddev for empty input")
+	}
+
+	got := stddev([]float64{2, 4, 4, 4, 5, 5, 7, 9})
+	want := 2.0
+	if !almostEqual(got, want) {
+		t.Errorf("stddev() = %v, want %v", got, want)
+	}
+}
+
+func TestMedian(t *testing.T) {
+	if median(nil) != 0.0 {
+		t.Errorf("expected 0 median for empty input")
+	}
+
+	if got := median([]float64{3, 1, 2}); got != 2 {
+		t.Errorf("median(odd) = %v, want 2", got)
+	}
+
+	if got := median([]float64{1, 2, 3, 4}); got != 2.5 {
+		t.Errorf("median(even) = %v, want 2.5", got)
+	}
+}
+
+func TestCovarianceMatrix(t *testing.T) {
+	currencies := []string{"A", "B"}
+	returns := map[string][]float64{
+		"A": {0.01, 0.02, -0.01},
+		"B": {0.01, 0.02, -0.01}, // identical series to A
+	}
+
+	sigma := covarianceMatrix(currencies, returns)
+	if !almostEqual(sigma[0][0], sigma[1][1]) {
+		t.Errorf("expected identical variances, got %v and %v", sigma[0][0], sigma[1][1])
+	}
+	if !almostEqual(sigma[0][1], sigma[0][0]) {
+		t.Errorf("expected cov(A,B) == var(A) for identical series, got %v vs %v", sigma[0][1], sigma[0][0])
+	}
+}
+
+func TestInvertMatrix(t *testing.T) {
+	identity := [][]float64{{1, 0}, {0, 1}}
+	inv, ok := invertMatrix(identity)
+	if !ok {
+		t.Fatalf("expected identity matrix to be invertible")
+	}
+	for i := range identity {
+		for j := range identity[i] {
+			if !almostEqual(inv[i][j], identity[i][j]) {
+				t.Errorf("inv[%d][%d] = %v, want %v", i, j, inv[i][j], identity[i][j])
+			}
+		}
+	}
+
+	m := [][]float64{{4, 7}, {2, 6}}
+	inv, ok = invertMatrix(m)
+	if !ok {
+		t.Fatalf("expected m to be invertible")
+	}
+	// [[4,7],[2,6]]^-1 = (1/10) * [[6,-7],[-2,4]]
+	want := [][]float64{{0.6, -0.7}, {-0.2, 0.4}}
+	for i := range want {
+		for j := range want[i] {
+			if !almostEqual(inv[i][j], want[i][j]) {
+				t.Errorf("inv[%d][%d] = %v, want %v", i, j, inv[i][j], want[i][j])
+			}
+		}
+	}
+
+	singular := [][]float64{{1, 2}, {2, 4}}
+	if _, ok := invertMatrix(singular); ok {
+		t.Errorf("expected singular matrix to be reported as non-invertible")
+	}
+}
@@ -3,6 +3,8 @@ package pvd
 import (
 	"context"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
@@ -60,10 +62,32 @@ type Strategy struct {
 	Verbose         bool             `json:"verbose"`
 	DryRun          bool             `json:"dryRun"`
 
+	// Weighting selects the WeightingScheme used to derive target weights.
+	// It defaults to "pvdot" (the original price-volume dot-product signal)
+	// when left empty.
+	Weighting WeightingType `json:"weighting"`
+
+	// PriceResolver configures the stablecoin proxies and bridge assets used
+	// to price a currency in BaseCurrency when no direct market exists.
+	PriceResolver PriceResolverConfig `json:"priceResolver"`
+
+	// RebalancePolicy controls the min-holding-time and cost-based order
+	// filters applied on top of the Threshold no-trade band.
+	RebalancePolicy RebalancePolicy `json:"rebalancePolicy"`
+
+	// Report configures the PortfolioTracker's in-memory buffer size and
+	// optional CSV/JSON output file.
+	Report PortfolioTrackerConfig `json:"report"`
+
 	// max amount to buy or sell per order
 	MaxAmount fixedpoint.Value `json:"maxAmount"`
 
-	set PVDotSet
+	set           PVDotSet
+	priceResolver *PriceResolver
+	tracker       *PortfolioTracker
+
+	feesMu                 sync.Mutex
+	feesSinceLastRebalance fixedpoint.Value
 }
 
 func (s *Strategy) ID() string {
@@ -79,6 +103,10 @@ func (s *Strategy) Validate() error {
 		return fmt.Errorf("maxAmount shoud not less than 0")
 	}
 
+	if _, err := NewWeightingScheme(s.Weighting); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -89,12 +117,33 @@ func (s *Strategy) Subscribe(session *bbgo.ExchangeSession) {
 }
 
 func (s *Strategy) Run(ctx context.Context, orderExecutor bbgo.OrderExecutor, session *bbgo.ExchangeSession) error {
+	weighting, err := NewWeightingScheme(s.Weighting)
+	if err != nil {
+		return err
+	}
+
 	iw := types.IntervalWindow{Interval: s.Interval, Window: s.Window}
-	s.set = PVDotSet{IntervalWindow: iw, session: session, BaseCurrency: s.BaseCurrency, QuoteCurrencies: s.QuoteCurrencies}
-	err := s.set.InitIndicators(ctx)
+	s.set = PVDotSet{IntervalWindow: iw, session: session, BaseCurrency: s.BaseCurrency, QuoteCurrencies: s.QuoteCurrencies, Weighting: weighting}
+	if err := s.set.InitIndicators(ctx); err != nil {
+		return err
+	}
+
+	s.priceResolver = NewPriceResolver(session, s.PriceResolver)
+
+	tracker, err := NewPortfolioTracker(s.Report)
 	if err != nil {
 		return err
 	}
+	s.tracker = tracker
+	s.feesSinceLastRebalance = fixedpoint.NewFromFloat(0.0)
+
+	// SetupHTTPRoutes is not called from here: bbgo's trader type-asserts
+	// every registered strategy for that method and calls it with its own
+	// *mux.Router, which is what actually wires the route into the running
+	// HTTP server.
+	session.UserDataStream.OnTrade(func(trade types.Trade) {
+		s.addRealizedFee(trade.Fee)
+	})
 
 	session.MarketDataStream.OnKLineClosed(func(kline types.KLine) {
 		s.set.Update(kline)
@@ -103,6 +152,25 @@ func (s *Strategy) Run(ctx context.Context, orderExecutor bbgo.OrderExecutor, se
 	return nil
 }
 
+// addRealizedFee accumulates a trade's fee until the next rebalance snapshot
+// consumes it via consumeRealizedFees.
+func (s *Strategy) addRealizedFee(fee fixedpoint.Value) {
+	s.feesMu.Lock()
+	s.feesSinceLastRebalance = s.feesSinceLastRebalance.Add(fee)
+	s.feesMu.Unlock()
+}
+
+// consumeRealizedFees returns the fees realized since the last call and
+// resets the accumulator.
+func (s *Strategy) consumeRealizedFees() fixedpoint.Value {
+	s.feesMu.Lock()
+	defer s.feesMu.Unlock()
+
+	fees := s.feesSinceLastRebalance
+	s.feesSinceLastRebalance = fixedpoint.NewFromFloat(0.0)
+	return fees
+}
+
 func (s *Strategy) rebalance(ctx context.Context, orderExecutor bbgo.OrderExecutor, session *bbgo.ExchangeSession) {
 	targetWeights := s.set.TargetWeights()
 
@@ -115,40 +183,42 @@ func (s *Strategy) rebalance(ctx context.Context, orderExecutor bbgo.OrderExecut
 	quantities := s.getQuantities(balances, targetWeights)
 	marketValues := ElementwiseProduct(prices, quantities)
 
-	orders := s.generateSubmitOrders(prices, marketValues, targetWeights)
+	orders, tradedWeightDelta := s.generateSubmitOrders(session, prices, marketValues, targetWeights)
 	for _, order := range orders {
 		log.Infof("generated submit order: %s", order.String())
 	}
 
+	currentWeights := Normalize(marketValues)
+
 	if s.DryRun {
+		// no order is ever sent in dry-run mode, so the snapshot must report
+		// zero orders submitted rather than the generated-but-unsent count.
+		s.tracker.Record(time.Now(), prices, quantities, marketValues, currentWeights, targetWeights, tradedWeightDelta, 0, s.consumeRealizedFees())
 		return
 	}
 
-	_, err = orderExecutor.SubmitOrders(ctx, orders...)
+	createdOrders, err := orderExecutor.SubmitOrders(ctx, orders...)
 	if err != nil {
 		log.WithError(err).Error("submit order error")
+		s.tracker.Record(time.Now(), prices, quantities, marketValues, currentWeights, targetWeights, tradedWeightDelta, 0, s.consumeRealizedFees())
 		return
 	}
+
+	s.tracker.Record(time.Now(), prices, quantities, marketValues, currentWeights, targetWeights, tradedWeightDelta, len(createdOrders), s.consumeRealizedFees())
 }
 
 func (s *Strategy) getPrices(ctx context.Context, session *bbgo.ExchangeSession, targetWeights map[string]fixedpoint.Value) (map[string]fixedpoint.Value, error) {
 	prices := make(map[string]fixedpoint.Value)
 
 	for currency := range targetWeights {
-		if currency == s.BaseCurrency {
-			prices[currency] = fixedpoint.One
-			continue
-		}
-
-		symbol := currency + s.BaseCurrency
-		ticker, err := session.Exchange.QueryTicker(ctx, symbol)
+		price, err := s.priceResolver.Resolve(ctx, currency, s.BaseCurrency)
 		if err != nil {
-			s.Notifiability.Notify("query ticker error: %s", err.Error())
-			log.WithError(err).Error("query ticker error")
+			s.Notifiability.Notify("resolve price error: %s", err.Error())
+			log.WithError(err).Error("resolve price error")
 			return prices, err
 		}
 
-		prices[currency] = ticker.Last
+		prices[currency] = price
 	}
 	return prices, nil
 }
@@ -165,11 +235,28 @@ func (s *Strategy) getQuantities(balances types.BalanceMap, targetWeights map[st
 	return quantities
 }
 
-func (s *Strategy) generateSubmitOrders(prices, marketValues map[string]fixedpoint.Value, targetWeights map[string]fixedpoint.Value) []types.SubmitOrder {
+// marketProvider is the subset of *bbgo.ExchangeSession that
+// generateSubmitOrders needs to look up a symbol's market metadata. Defining
+// it locally lets tests drive generateSubmitOrders with a fake instead of a
+// real ExchangeSession.
+type marketProvider interface {
+	Market(symbol string) (types.Market, bool)
+}
+
+// generateSubmitOrders returns the orders it generated and, for each
+// currency an order was actually generated for, the signed weight change
+// that order represents (positive for buy, negative for sell). Currencies
+// that were skipped -- by the threshold, MinHoldingTime, min notional, or
+// the cost model -- have no entry, so turnover derived from this map
+// reflects only the trading that actually happened, not the full drift to
+// target.
+func (s *Strategy) generateSubmitOrders(markets marketProvider, prices, marketValues map[string]fixedpoint.Value, targetWeights map[string]fixedpoint.Value) ([]types.SubmitOrder, map[string]fixedpoint.Value) {
 	var submitOrders []types.SubmitOrder
+	tradedWeightDelta := make(map[string]fixedpoint.Value)
 
 	currentWeights := Normalize(marketValues)
 	totalValue := Sum(marketValues)
+	now := time.Now()
 
 	log.Infof("total value: %f", totalValue.Float64())
 
@@ -199,7 +286,16 @@ func (s *Strategy) generateSubmitOrders(prices, marketValues map[string]fixedpoi
 			continue
 		}
 
-		quantity := weightDifference.Mul(totalValue).Div(currentPrice)
+		if !s.RebalancePolicy.canRebalance(symbol, now) {
+			log.Infof("%s rebalanced too recently, skipping until min holding time elapses", symbol)
+			continue
+		}
+
+		// trade back only to the edge of the no-trade band instead of all the
+		// way to the target weight, to reduce turnover.
+		tradeWeight := bandEdgeTradeWeight(currentWeight, targetWeight, s.Threshold)
+
+		quantity := tradeWeight.Mul(totalValue).Div(currentPrice)
 
 		side := types.SideTypeBuy
 		if quantity.Sign() < 0 {
@@ -217,6 +313,38 @@ func (s *Strategy) generateSubmitOrders(prices, marketValues map[string]fixedpoi
 				s.MaxAmount)
 		}
 
+		notional := quantity.Mul(currentPrice)
+
+		market, ok := markets.Market(symbol)
+		if !ok {
+			log.Infof("%s market metadata not found, skipping order to avoid an unvalidated dust trade", symbol)
+			continue
+		}
+		if notional.Compare(market.MinNotional) < 0 {
+			log.Infof("%s notional %v is below the market's min notional %v, skipping dust order",
+				symbol, notional, market.MinNotional)
+			continue
+		}
+
+		// the tracking-error improvement this trade buys is the reduction in
+		// squared tracking error (the same units PortfolioTracker reports,
+		// scaled back to notional so it's comparable to cost) from closing
+		// the executed weight change out of the full gap. Using notional
+		// itself here made cost/(improvement*MaxCostRatio) reduce to a
+		// constant comparison between FeeRate+SlippageRate and MaxCostRatio
+		// since notional cancels on both sides -- this version grows
+		// superlinearly with how much of the gap is actually closed, so a
+		// trade barely past the threshold no longer reads the same as one
+		// that closes most of a large deviation.
+		residualBefore := weightDifference.Abs()
+		executedWeightChange := notional.Div(totalValue)
+		residualAfter := residualBefore.Sub(executedWeightChange)
+		trackingErrorImprovement := residualBefore.Mul(residualBefore).Sub(residualAfter.Mul(residualAfter)).Mul(totalValue)
+		if !s.RebalancePolicy.CostModel.allows(notional, trackingErrorImprovement) {
+			log.Infof("%s estimated cost for notional %v exceeds the tracking-error improvement, skipping", symbol, notional)
+			continue
+		}
+
 		order := types.SubmitOrder{
 			Symbol:   symbol,
 			Side:     side,
@@ -224,8 +352,15 @@ func (s *Strategy) generateSubmitOrders(prices, marketValues map[string]fixedpoi
 			Quantity: quantity}
 
 		submitOrders = append(submitOrders, order)
+		s.RebalancePolicy.markRebalanced(symbol, now)
+
+		weightDelta := executedWeightChange
+		if side == types.SideTypeSell {
+			weightDelta = executedWeightChange.Mul(fixedpoint.NewFromFloat(-1))
+		}
+		tradedWeightDelta[currency] = weightDelta
 	}
-	return submitOrders
+	return submitOrders, tradedWeightDelta
 }
 
 func (s *Strategy) getSymbols() []string {
@@ -0,0 +1,77 @@
+package pvd
+
+import (
+	"testing"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+type fakeMarketProvider struct {
+	markets map[string]types.Market
+}
+
+func (f fakeMarketProvider) Market(symbol string) (types.Market, bool) {
+	m, ok := f.markets[symbol]
+	return m, ok
+}
+
+func TestGenerateSubmitOrders_CostModelScalesWithGapClosed(t *testing.T) {
+	costModel := &CostModel{
+		FeeRate:      fixedpoint.NewFromFloat(0.001),
+		SlippageRate: fixedpoint.NewFromFloat(0.001),
+		MaxCostRatio: fixedpoint.NewFromFloat(0.01),
+	}
+	markets := fakeMarketProvider{markets: map[string]types.Market{
+		"BTCUSD": {MinNotional: fixedpoint.NewFromFloat(1)},
+	}}
+
+	newStrategy := func() *Strategy {
+		return &Strategy{
+			BaseCurrency: "USD",
+			Threshold:    fixedpoint.NewFromFloat(0.05),
+			RebalancePolicy: RebalancePolicy{
+				CostModel: costModel,
+			},
+		}
+	}
+
+	prices := map[string]fixedpoint.Value{"BTC": fixedpoint.NewFromFloat(100)}
+	// ETH's current and target weight always match, so it never clears the
+	// threshold and its candidate trade never reaches the cost model.
+	marketValues := map[string]fixedpoint.Value{
+		"BTC": fixedpoint.NewFromFloat(5100),
+		"ETH": fixedpoint.NewFromFloat(4900),
+	}
+
+	// Just over the threshold: the gap this trade closes is tiny, so its
+	// tracking-error improvement shouldn't clear the cost bar even though
+	// FeeRate+SlippageRate (0.002) is well under MaxCostRatio (0.01).
+	s := newStrategy()
+	targetWeights := map[string]fixedpoint.Value{
+		"BTC": fixedpoint.NewFromFloat(0.561),
+		"ETH": fixedpoint.NewFromFloat(0.49),
+	}
+	orders, _ := s.generateSubmitOrders(markets, prices, marketValues, targetWeights)
+	if len(orders) != 0 {
+		t.Errorf("expected a trade barely past the threshold to be rejected by the cost model, got %d orders", len(orders))
+	}
+
+	// Far over the threshold: this trade closes most of a large deviation,
+	// so the same fee/slippage rates should now clear the cost bar.
+	s = newStrategy()
+	targetWeights = map[string]fixedpoint.Value{
+		"BTC": fixedpoint.NewFromFloat(0.01),
+		"ETH": fixedpoint.NewFromFloat(0.49),
+	}
+	orders, tradedWeightDelta := s.generateSubmitOrders(markets, prices, marketValues, targetWeights)
+	if len(orders) != 1 {
+		t.Errorf("expected a trade closing most of a large deviation to be allowed by the cost model, got %d orders", len(orders))
+	}
+	if _, ok := tradedWeightDelta["ETH"]; ok {
+		t.Errorf("expected ETH, whose trade was skipped under the threshold, to have no tradedWeightDelta entry")
+	}
+	if delta, ok := tradedWeightDelta["BTC"]; !ok || delta.Sign() >= 0 {
+		t.Errorf("expected a negative tradedWeightDelta for BTC's sell, got %v (present=%v)", delta.Float64(), ok)
+	}
+}
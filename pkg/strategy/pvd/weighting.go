@@ -0,0 +1,155 @@
+package pvd
+
+import (
+	"fmt"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// WeightingType selects the WeightingScheme used to turn tracked kline
+// windows into target portfolio weights.
+type WeightingType string
+
+const (
+	WeightingPVDot      WeightingType = "pvdot"
+	WeightingRiskParity WeightingType = "riskparity"
+	WeightingMaxSharpe  WeightingType = "maxsharpe"
+)
+
+// WeightingScheme computes target portfolio weights from the rolling kline
+// window tracked for each quote currency's market against BaseCurrency.
+// Implementations should return weights that sum to 1.
+type WeightingScheme interface {
+	Weights(series map[string]types.KLineWindow) map[string]fixedpoint.Value
+}
+
+// NewWeightingScheme constructs the WeightingScheme identified by t. An
+// empty t defaults to WeightingPVDot so existing configs keep behaving the
+// way they used to before this field was introduced.
+func NewWeightingScheme(t WeightingType) (WeightingScheme, error) {
+	switch t {
+	case "", WeightingPVDot:
+		return &PVDotWeighting{}, nil
+	case WeightingRiskParity:
+		return &RiskParityWeighting{}, nil
+	case WeightingMaxSharpe:
+		return &MaxSharpeWeighting{}, nil
+	default:
+		return nil, fmt.Errorf("pvd: unsupported weighting scheme %q", t)
+	}
+}
+
+// PVDotWeighting is the original scheme: the weight of a currency is
+// proportional to the dot product of its close prices and volumes over the
+// window.
+type PVDotWeighting struct{}
+
+func (w *PVDotWeighting) Weights(series map[string]types.KLineWindow) map[string]fixedpoint.Value {
+	dots := make(map[string]fixedpoint.Value, len(series))
+	for currency, window := range series {
+		dot := fixedpoint.NewFromFloat(0.0)
+		for _, k := range window {
+			dot = dot.Add(k.Close.Mul(k.Volume))
+		}
+		dots[currency] = dot
+	}
+	return Normalize(dots)
+}
+
+// RiskParityWeighting (a.k.a. inverse-volatility weighting) assigns each
+// currency a weight proportional to 1/sigma, where sigma is the standard
+// deviation of its log returns over the window. Lower volatility assets get
+// a larger share of the portfolio.
+type RiskParityWeighting struct{}
+
+func (w *RiskParityWeighting) Weights(series map[string]types.KLineWindow) map[string]fixedpoint.Value {
+	invVols := make(map[string]fixedpoint.Value, len(series))
+
+	var observedInvVols []float64
+	var flatCurrencies []string
+	for currency, window := range series {
+		sigma := stddev(logReturns(closesOf(window)))
+		if sigma <= 0 {
+			flatCurrencies = append(flatCurrencies, currency)
+			continue
+		}
+
+		invVol := 1.0 / sigma
+		observedInvVols = append(observedInvVols, invVol)
+		invVols[currency] = fixedpoint.NewFromFloat(invVol)
+	}
+
+	// no observed volatility yet: fall back to the median of the other
+	// assets' inverse-vol rather than a bare 1, which would be negligible
+	// next to typical crypto 1/sigma values and starve the asset; the median
+	// gives it a share comparable to the other assets'.
+	fallback := fixedpoint.One
+	if len(observedInvVols) > 0 {
+		fallback = fixedpoint.NewFromFloat(median(observedInvVols))
+	}
+	for _, currency := range flatCurrencies {
+		invVols[currency] = fallback
+	}
+
+	return Normalize(invVols)
+}
+
+// MaxSharpeWeighting estimates the mean return vector mu and covariance
+// matrix Sigma of the tracked currencies over the window and solves the
+// (unconstrained) max-Sharpe weights w = Sigma^-1 * mu, clipping negative
+// weights to 0 and renormalizing the rest to sum to 1.
+type MaxSharpeWeighting struct{}
+
+func (w *MaxSharpeWeighting) Weights(series map[string]types.KLineWindow) map[string]fixedpoint.Value {
+	currencies := make([]string, 0, len(series))
+	for currency := range series {
+		currencies = append(currencies, currency)
+	}
+
+	returns := make(map[string][]float64, len(series))
+	mu := make([]float64, len(currencies))
+	for i, currency := range currencies {
+		rs := logReturns(closesOf(series[currency]))
+		returns[currency] = rs
+		mu[i] = mean(rs)
+	}
+
+	sigma := covarianceMatrix(currencies, returns)
+	inv, ok := invertMatrix(sigma)
+
+	weights := make(map[string]fixedpoint.Value, len(currencies))
+	if !ok {
+		// Sigma is singular (e.g. too few observations): fall back to an
+		// equal-weight portfolio rather than failing the rebalance.
+		for _, currency := range currencies {
+			weights[currency] = fixedpoint.One
+		}
+		return Normalize(weights)
+	}
+
+	raw := matVec(inv, mu)
+	for i, currency := range currencies {
+		w := raw[i]
+		if w < 0 {
+			w = 0
+		}
+		weights[currency] = fixedpoint.NewFromFloat(w)
+	}
+
+	if Sum(weights).Sign() == 0 {
+		// every weight clipped to 0: fall back to an equal-weight portfolio.
+		for _, currency := range currencies {
+			weights[currency] = fixedpoint.One
+		}
+	}
+	return Normalize(weights)
+}
+
+func closesOf(window types.KLineWindow) []float64 {
+	closes := make([]float64, 0, len(window))
+	for _, k := range window {
+		closes = append(closes, k.Close.Float64())
+	}
+	return closes
+}
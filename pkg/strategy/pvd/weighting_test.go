@@ -0,0 +1,106 @@
+package pvd
+
+import (
+	"testing"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func klineWindow(closes []float64) types.KLineWindow {
+	var window types.KLineWindow
+	for _, c := range closes {
+		window = append(window, types.KLine{Close: fixedpoint.NewFromFloat(c)})
+	}
+	return window
+}
+
+func TestRiskParityWeighting_FlatAssetFallsBackToMedianInvVol(t *testing.T) {
+	w := &RiskParityWeighting{}
+
+	series := map[string]types.KLineWindow{
+		// volatile assets with real 1/sigma on the order of tens.
+		"BTC": klineWindow([]float64{100, 102, 99, 103, 98}),
+		"ETH": klineWindow([]float64{50, 53, 48, 52, 49}),
+		// flat/no-data asset: zero observed volatility.
+		"USDC": klineWindow([]float64{1, 1, 1, 1, 1}),
+	}
+
+	weights := w.Weights(series)
+
+	if weights["USDC"].Float64() <= 0.1 {
+		t.Errorf("expected the flat asset to get a weight comparable to the volatile assets, got %v (weights=%v)",
+			weights["USDC"].Float64(), weights)
+	}
+}
+
+func TestPVDotWeighting_NormalizesToOne(t *testing.T) {
+	w := &PVDotWeighting{}
+
+	series := map[string]types.KLineWindow{
+		"BTC": {
+			{Close: fixedpoint.NewFromFloat(100), Volume: fixedpoint.NewFromFloat(10)},
+			{Close: fixedpoint.NewFromFloat(110), Volume: fixedpoint.NewFromFloat(5)},
+		},
+		"ETH": {
+			{Close: fixedpoint.NewFromFloat(50), Volume: fixedpoint.NewFromFloat(20)},
+		},
+	}
+
+	weights := w.Weights(series)
+
+	sum := Sum(weights).Float64()
+	if sum < 0.999 || sum > 1.001 {
+		t.Errorf("expected weights to sum to 1, got %v", sum)
+	}
+}
+
+func TestMaxSharpeWeighting_ClipsNegativeWeight(t *testing.T) {
+	w := &MaxSharpeWeighting{}
+
+	series := map[string]types.KLineWindow{
+		// consistently rising: positive mean return.
+		"UP": klineWindow([]float64{100, 102, 104, 106, 108, 110}),
+		// consistently falling: negative mean return, so its unconstrained
+		// Sigma^-1*mu weight comes out negative and must be clipped to 0.
+		"DOWN": klineWindow([]float64{100, 98, 96, 94, 92, 90}),
+	}
+
+	weights := w.Weights(series)
+
+	if got := weights["DOWN"].Float64(); got != 0 {
+		t.Errorf("expected the consistently-declining asset's negative raw weight to be clipped to 0, got %v", got)
+	}
+	if got := weights["UP"].Float64(); got <= 0 {
+		t.Errorf("expected the consistently-rising asset to receive a positive weight, got %v", got)
+	}
+
+	sum := Sum(weights).Float64()
+	if sum < 0.999 || sum > 1.001 {
+		t.Errorf("expected weights to sum to 1, got %v", sum)
+	}
+}
+
+func TestMaxSharpeWeighting_SingularCovarianceFallsBackToEqualWeight(t *testing.T) {
+	w := &MaxSharpeWeighting{}
+
+	// identical price series for both currencies makes their covariance
+	// matrix singular (proportional rows), which invertMatrix can't invert.
+	prices := []float64{100, 102, 101, 103, 99, 104}
+	series := map[string]types.KLineWindow{
+		"A": klineWindow(prices),
+		"B": klineWindow(prices),
+	}
+
+	weights := w.Weights(series)
+
+	if weights["A"].Float64() != weights["B"].Float64() {
+		t.Errorf("expected the equal-weight fallback for a singular covariance matrix, got A=%v B=%v",
+			weights["A"].Float64(), weights["B"].Float64())
+	}
+
+	sum := Sum(weights).Float64()
+	if sum < 0.999 || sum > 1.001 {
+		t.Errorf("expected weights to sum to 1, got %v", sum)
+	}
+}